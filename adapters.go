@@ -1,7 +1,20 @@
 package adapters
 
 import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
 	"iter"
+	"math/rand/v2"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 func Filter[T any](s iter.Seq[T], pred func(T) bool) iter.Seq[T] {
@@ -58,6 +71,29 @@ func Reduce[T, R any](s iter.Seq[T], initial R, reducer func(R, T) R) R {
 	return result
 }
 
+func Reduce2[K, V, R any](s iter.Seq2[K, V], initial R, reducer func(R, K, V) R) R {
+	result := initial
+	for k, v := range s {
+		result = reducer(result, k, v)
+	}
+	return result
+}
+
+func FoldMap[T, A, R any](s iter.Seq[T], initial A, accumulate func(A, T) A, finish func(A) R) R {
+	return finish(Reduce(s, initial, accumulate))
+}
+
+// ReduceRight folds s from the last element to the first. The whole of s is
+// buffered first.
+func ReduceRight[T, R any](s iter.Seq[T], initial R, reducer func(T, R) R) R {
+	buf := Collect(s)
+	result := initial
+	for i := len(buf) - 1; i >= 0; i-- {
+		result = reducer(buf[i], result)
+	}
+	return result
+}
+
 func Take[T any](s iter.Seq[T], n int) iter.Seq[T] {
 	return func(yield func(T) bool) {
 		count := 0
@@ -88,6 +124,32 @@ func Take2[K, V any](s iter.Seq2[K, V], n int) iter.Seq2[K, V] {
 	}
 }
 
+func TakeWhile[T any](s iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if !pred(v) {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func TakeWhile2[K, V any](s iter.Seq2[K, V], pred func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range s {
+			if !pred(k, v) {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 func Skip[T any](s iter.Seq[T], n int) iter.Seq[T] {
 	return func(yield func(T) bool) {
 		count := 0
@@ -116,6 +178,40 @@ func Skip2[K, V any](s iter.Seq2[K, V], n int) iter.Seq2[K, V] {
 	}
 }
 
+func SkipWhile[T any](s iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		skipping := true
+		for v := range s {
+			if skipping {
+				if pred(v) {
+					continue
+				}
+				skipping = false
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func SkipWhile2[K, V any](s iter.Seq2[K, V], pred func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		skipping := true
+		for k, v := range s {
+			if skipping {
+				if pred(k, v) {
+					continue
+				}
+				skipping = false
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 func Zip[T, U any](s1 iter.Seq[T], s2 iter.Seq[U]) iter.Seq2[T, U] {
 	return func(yield func(T, U) bool) {
 		next1, stop1 := iter.Pull(s1)
@@ -139,6 +235,101 @@ func Zip[T, U any](s1 iter.Seq[T], s2 iter.Seq[U]) iter.Seq2[T, U] {
 	}
 }
 
+func ZipWith[A, B, R any](a iter.Seq[A], b iter.Seq[B], combine func(A, B) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v1, v2 := range Zip(a, b) {
+			if !yield(combine(v1, v2)) {
+				return
+			}
+		}
+	}
+}
+
+func Zip3[A, B, C, R any](a iter.Seq[A], b iter.Seq[B], c iter.Seq[C], combine func(A, B, C) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		next1, stop1 := iter.Pull(a)
+		next2, stop2 := iter.Pull(b)
+		next3, stop3 := iter.Pull(c)
+		defer stop1()
+		defer stop2()
+		defer stop3()
+
+		for {
+			v1, ok1 := next1()
+			if !ok1 {
+				return
+			}
+			v2, ok2 := next2()
+			if !ok2 {
+				return
+			}
+			v3, ok3 := next3()
+			if !ok3 {
+				return
+			}
+			if !yield(combine(v1, v2, v3)) {
+				return
+			}
+		}
+	}
+}
+
+// ZipN yields one slice per step holding the next element of every sequence,
+// stopping when the shortest is exhausted. Each slice is freshly allocated.
+func ZipN[T any](seqs ...iter.Seq[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if len(seqs) == 0 {
+			return
+		}
+		nexts := make([]func() (T, bool), len(seqs))
+		for i, s := range seqs {
+			next, stop := iter.Pull(s)
+			defer stop()
+			nexts[i] = next
+		}
+
+		for {
+			row := make([]T, len(nexts))
+			for i, next := range nexts {
+				v, ok := next()
+				if !ok {
+					return
+				}
+				row[i] = v
+			}
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+func ZipLongest[T, U any](s1 iter.Seq[T], s2 iter.Seq[U], pad1 T, pad2 U) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		next1, stop1 := iter.Pull(s1)
+		next2, stop2 := iter.Pull(s2)
+		defer stop1()
+		defer stop2()
+
+		for {
+			v1, ok1 := next1()
+			v2, ok2 := next2()
+			if !ok1 && !ok2 {
+				return
+			}
+			if !ok1 {
+				v1 = pad1
+			}
+			if !ok2 {
+				v2 = pad2
+			}
+			if !yield(v1, v2) {
+				return
+			}
+		}
+	}
+}
+
 func FlatMap[T, R any](s iter.Seq[T], transform func(T) iter.Seq[R]) iter.Seq[R] {
 	return func(yield func(R) bool) {
 		for v := range s {
@@ -165,6 +356,22 @@ func FlatMap2[K1, V1, K2, V2 any](s iter.Seq2[K1, V1], transform func(K1, V1) it
 	}
 }
 
+func FlatMapToSeq[K, V, R any](s iter.Seq2[K, V], transform func(K, V) iter.Seq[R]) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for k, v := range s {
+			innerSeq := transform(k, v)
+			for innerV := range innerSeq {
+				if !yield(innerV) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Flatten unpacks sequences, slices and single values of T held in an
+// iter.Seq[any]. It panics on any other dynamic type rather than dropping the
+// item. Prefer FlattenSeq or FlattenSlices, which are type-safe.
 func Flatten[T any](s iter.Seq[any]) iter.Seq[T] {
 	return func(yield func(T) bool) {
 		for item := range s {
@@ -201,6 +408,49 @@ func Flatten[T any](s iter.Seq[any]) iter.Seq[T] {
 				if !yield(v) {
 					return
 				}
+			default:
+				panic(fmt.Sprintf("adapters: Flatten cannot unpack %T into %v", item, reflect.TypeFor[T]()))
+			}
+		}
+	}
+}
+
+func FlattenSeq[T any](s iter.Seq[iter.Seq[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for inner := range s {
+			for v := range inner {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func FlattenSlices[T any](s iter.Seq[[]T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for inner := range s {
+			for _, v := range inner {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func Flatten3[T any](s iter.Seq[iter.Seq[iter.Seq[T]]]) iter.Seq[T] {
+	return FlattenSeq(FlattenSeq(s))
+}
+
+func FlattenPtr[T any](s iter.Seq[*T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for p := range s {
+			if p == nil {
+				continue
+			}
+			if !yield(*p) {
+				return
 			}
 		}
 	}
@@ -229,3 +479,2300 @@ func FilterMap2[K1, V1, K2, V2 any](s iter.Seq2[K1, V1], transform func(K1, V1)
 		}
 	}
 }
+
+func Chain[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range seqs {
+			for v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func Chain2[K, V any](seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range seqs {
+			for k, v := range s {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func Collect[T any](s iter.Seq[T]) []T {
+	return CollectInto(s, nil)
+}
+
+func CollectInto[T any](s iter.Seq[T], dst []T) []T {
+	for v := range s {
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+// CollectMap drains s into a map. When a key is yielded more than once, the
+// last value wins.
+func CollectMap[K comparable, V any](s iter.Seq2[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range s {
+		result[k] = v
+	}
+	return result
+}
+
+// Distinct yields the first occurrence of each value. Every distinct value seen
+// is kept in memory for the lifetime of the iteration.
+func Distinct[T comparable](s iter.Seq[T]) iter.Seq[T] {
+	return DistinctBy(s, func(v T) T { return v })
+}
+
+// DistinctBy yields the first element for each distinct key. Every distinct key
+// seen is kept in memory for the lifetime of the iteration.
+func DistinctBy[T any, K comparable](s iter.Seq[T], key func(T) K) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range s {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func Dedup[T comparable](s iter.Seq[T]) iter.Seq[T] {
+	return DedupBy(s, func(a, b T) bool { return a == b })
+}
+
+func DedupBy[T any](s iter.Seq[T], eq func(a, b T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var prev T
+		first := true
+		for v := range s {
+			if !first && eq(prev, v) {
+				continue
+			}
+			first = false
+			prev = v
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// maxPrealloc caps capacity allocated up front from a caller-supplied size, so
+// huge sizes grow only as elements actually arrive.
+const maxPrealloc = 64
+
+// Chunk groups consecutive elements into slices of length size, with a shorter
+// final chunk for any remainder. Each chunk has its own backing array. Chunk
+// panics if size <= 0.
+func Chunk[T any](s iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("adapters: Chunk size must be positive")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, min(size, maxPrealloc))
+		for v := range s {
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window yields every run of size consecutive elements, advancing one element
+// at a time. Each window is a fresh copy. Window panics if size <= 0.
+func Window[T any](s iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("adapters: Window size must be positive")
+	}
+	return func(yield func([]T) bool) {
+		next, stop := iter.Pull(s)
+		defer stop()
+
+		buf := make([]T, 0, min(size, maxPrealloc))
+		for len(buf) < size {
+			v, ok := next()
+			if !ok {
+				return
+			}
+			buf = append(buf, v)
+		}
+		for {
+			window := make([]T, size)
+			copy(window, buf)
+			if !yield(window) {
+				return
+			}
+			v, ok := next()
+			if !ok {
+				return
+			}
+			copy(buf, buf[1:])
+			buf[size-1] = v
+		}
+	}
+}
+
+func Enumerate[T any](s iter.Seq[T]) iter.Seq2[int, T] {
+	return EnumerateFrom(s, 0)
+}
+
+func EnumerateFrom[T any](s iter.Seq[T], start int) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := start
+		for v := range s {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func Keys[K, V any](s iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func Values[K, V any](s iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Unzip drains s into two parallel slices of equal length. The whole sequence
+// is buffered in memory.
+func Unzip[K, V any](s iter.Seq2[K, V]) ([]K, []V) {
+	var keys []K
+	var values []V
+	for k, v := range s {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values
+}
+
+func Count[T any](s iter.Seq[T]) int {
+	count := 0
+	for range s {
+		count++
+	}
+	return count
+}
+
+func Count2[K, V any](s iter.Seq2[K, V]) int {
+	count := 0
+	for range s {
+		count++
+	}
+	return count
+}
+
+func CountFunc[T any](s iter.Seq[T], pred func(T) bool) int {
+	count := 0
+	for v := range s {
+		if pred(v) {
+			count++
+		}
+	}
+	return count
+}
+
+func Any[T any](s iter.Seq[T], pred func(T) bool) bool {
+	for v := range s {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func Any2[K, V any](s iter.Seq2[K, V], pred func(K, V) bool) bool {
+	for k, v := range s {
+		if pred(k, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func All[T any](s iter.Seq[T], pred func(T) bool) bool {
+	for v := range s {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func All2[K, V any](s iter.Seq2[K, V], pred func(K, V) bool) bool {
+	for k, v := range s {
+		if !pred(k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func None[T any](s iter.Seq[T], pred func(T) bool) bool {
+	return !Any(s, pred)
+}
+
+func None2[K, V any](s iter.Seq2[K, V], pred func(K, V) bool) bool {
+	return !Any2(s, pred)
+}
+
+func Find[T any](s iter.Seq[T], pred func(T) bool) (T, bool) {
+	for v := range s {
+		if pred(v) {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func FirstFunc[T any](s iter.Seq[T], pred func(T) bool) (T, bool) {
+	return Find(s, pred)
+}
+
+// LastFunc returns the last element satisfying pred, draining s while holding
+// on to the latest match only. The bool is false if nothing matched.
+func LastFunc[T any](s iter.Seq[T], pred func(T) bool) (T, bool) {
+	return Last(Filter(s, pred))
+}
+
+func FindIndex[T any](s iter.Seq[T], pred func(T) bool) int {
+	i := 0
+	for v := range s {
+		if pred(v) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+func Contains[T comparable](s iter.Seq[T], target T) bool {
+	return ContainsFunc(s, func(v T) bool { return v == target })
+}
+
+func ContainsFunc[T any](s iter.Seq[T], pred func(T) bool) bool {
+	return Any(s, pred)
+}
+
+func Min[T cmp.Ordered](s iter.Seq[T]) (T, bool) {
+	return MinBy(s, cmp.Compare[T])
+}
+
+func Max[T cmp.Ordered](s iter.Seq[T]) (T, bool) {
+	return MaxBy(s, cmp.Compare[T])
+}
+
+// MinBy returns the smallest element according to compare, keeping the first
+// one on ties. The bool is false if s is empty.
+func MinBy[T any](s iter.Seq[T], compare func(a, b T) int) (T, bool) {
+	var result T
+	found := false
+	for v := range s {
+		if !found || compare(v, result) < 0 {
+			result = v
+			found = true
+		}
+	}
+	return result, found
+}
+
+// MaxBy returns the largest element according to compare, keeping the first
+// one on ties. The bool is false if s is empty.
+func MaxBy[T any](s iter.Seq[T], compare func(a, b T) int) (T, bool) {
+	var result T
+	found := false
+	for v := range s {
+		if !found || compare(v, result) > 0 {
+			result = v
+			found = true
+		}
+	}
+	return result, found
+}
+
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+func Sum[T Numeric](s iter.Seq[T]) T {
+	return Reduce(s, 0, func(acc, v T) T { return acc + v })
+}
+
+func Product[T Numeric](s iter.Seq[T]) T {
+	return Reduce(s, 1, func(acc, v T) T { return acc * v })
+}
+
+func SumFunc[T any, N Numeric](s iter.Seq[T], selector func(T) N) N {
+	return Reduce(s, 0, func(acc N, v T) N { return acc + selector(v) })
+}
+
+func ForEach[T any](s iter.Seq[T], fn func(T)) {
+	for v := range s {
+		fn(v)
+	}
+}
+
+func ForEach2[K, V any](s iter.Seq2[K, V], fn func(K, V)) {
+	for k, v := range s {
+		fn(k, v)
+	}
+}
+
+func ForEachErr[T any](s iter.Seq[T], fn func(T) error) error {
+	for v := range s {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan yields the accumulator after each element is folded in. The initial
+// value itself is not yielded, so the output has the same length as s.
+func Scan[T, R any](s iter.Seq[T], initial R, reducer func(R, T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		result := initial
+		for v := range s {
+			result = reducer(result, v)
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// Partition drains s, routing each element to matched or unmatched. Both
+// slices are non-nil, even when empty.
+func Partition[T any](s iter.Seq[T], pred func(T) bool) (matched []T, unmatched []T) {
+	matched = []T{}
+	unmatched = []T{}
+	for v := range s {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return matched, unmatched
+}
+
+// GroupBy drains s into buckets keyed by key, preserving the order of elements
+// within each bucket.
+func GroupBy[T any, K comparable](s iter.Seq[T], key func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for v := range s {
+		k := key(v)
+		result[k] = append(result[k], v)
+	}
+	return result
+}
+
+// Reverse yields the elements of s back to front. The whole of s is buffered on
+// the first step of iteration.
+func Reverse[T any](s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := Collect(s)
+		for i := len(buf) - 1; i >= 0; i-- {
+			if !yield(buf[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Sort yields the elements of s in ascending order. The whole of s is buffered
+// on the first step of iteration.
+func Sort[T cmp.Ordered](s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := Collect(s)
+		slices.Sort(buf)
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SortBy yields the elements of s stably sorted by compare. The whole of s is
+// buffered on the first step of iteration.
+func SortBy[T any](s iter.Seq[T], compare func(a, b T) int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := Collect(s)
+		slices.SortStableFunc(buf, compare)
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func First[T any](s iter.Seq[T]) (T, bool) {
+	for v := range s {
+		return v, true
+	}
+	var zero T
+	return zero, false
+}
+
+func Last[T any](s iter.Seq[T]) (T, bool) {
+	var result T
+	found := false
+	for v := range s {
+		result = v
+		found = true
+	}
+	return result, found
+}
+
+func Nth[T any](s iter.Seq[T], n int) (T, bool) {
+	if n < 0 {
+		var zero T
+		return zero, false
+	}
+	return First(Skip(s, n))
+}
+
+func Repeat[T any](v T, count int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for range count {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func RepeatForever[T any](v T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for yield(v) {
+		}
+	}
+}
+
+// Cycle yields the elements of s over and over. The first pass is buffered and
+// replayed afterwards, so the result is infinite unless s is empty.
+func Cycle[T any](s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var buf []T
+		for v := range s {
+			buf = append(buf, v)
+			if !yield(v) {
+				return
+			}
+		}
+		if len(buf) == 0 {
+			return
+		}
+		for {
+			for _, v := range buf {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func Range(start, end int) iter.Seq[int] {
+	return RangeStep(start, end, 1)
+}
+
+// RangeStep yields start, start+step, ... while below end for a positive step,
+// or while above end for a negative step. It panics if step is zero.
+func RangeStep(start, end, step int) iter.Seq[int] {
+	if step == 0 {
+		panic("adapters: RangeStep step must not be zero")
+	}
+	// The remaining distance is compared as uint before stepping, so i never
+	// wraps around when end is near the limits of int.
+	return func(yield func(int) bool) {
+		if step > 0 {
+			for i := start; i < end; i += step {
+				if !yield(i) || uint(end-i) <= uint(step) {
+					return
+				}
+			}
+			return
+		}
+		for i := start; i > end; i += step {
+			if !yield(i) || uint(i-end) <= uint(-step) {
+				return
+			}
+		}
+	}
+}
+
+// MapParallel applies transform across a pool of workers goroutines and yields
+// the results in input order. workers <= 0 means runtime.NumCPU(). s is pulled
+// on the consumer's goroutine, at most workers elements ahead of the consumer.
+// When iteration ends, s is stopped and the in-flight transforms are waited for
+// before MapParallel returns.
+func MapParallel[T, R any](s iter.Seq[T], workers int, transform func(T) R) iter.Seq[R] {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return func(yield func(R) bool) {
+		type job struct {
+			v   T
+			out chan R
+		}
+		next, stop := iter.Pull(s)
+		defer stop()
+
+		var abandoned atomic.Bool
+		jobs := make(chan job, workers)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer close(jobs)
+		defer abandoned.Store(true)
+
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					if !abandoned.Load() {
+						j.out <- transform(j.v)
+					}
+				}
+			}()
+		}
+
+		// At most workers jobs are pending, so sends on jobs never block.
+		var pending []chan R
+		submit := func() {
+			if v, ok := next(); ok {
+				out := make(chan R, 1)
+				jobs <- job{v: v, out: out}
+				pending = append(pending, out)
+			}
+		}
+		for range workers {
+			submit()
+		}
+		for len(pending) > 0 {
+			out := pending[0]
+			pending = pending[1:]
+			if !yield(<-out) {
+				return
+			}
+			submit()
+		}
+	}
+}
+
+// WithContext stops the sequence once ctx is done. ctx is checked before each
+// element is yielded.
+func WithContext[T any](ctx context.Context, s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if ctx.Err() != nil {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// WithContext2 stops the sequence once ctx is done. ctx is checked before each
+// pair is yielded.
+func WithContext2[K, V any](ctx context.Context, s iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range s {
+			if ctx.Err() != nil {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func FromChannel[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ToChannel pumps s into the returned channel from a new goroutine and closes
+// the channel when s is exhausted or ctx is done. A consumer that stops
+// receiving must cancel ctx, otherwise the goroutine blocks forever.
+func ToChannel[T any](ctx context.Context, s iter.Seq[T], buffer int) <-chan T {
+	ch := make(chan T, buffer)
+	go func() {
+		defer close(ch)
+		for v := range s {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func FromSlice[T any](xs []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range xs {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// FromMap yields the entries of m. As with ranging over a map, the order is
+// unspecified and may differ between iterations.
+func FromMap[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func Of[T any](xs ...T) iter.Seq[T] {
+	return FromSlice(xs)
+}
+
+func Empty[T any]() iter.Seq[T] {
+	return func(yield func(T) bool) {}
+}
+
+// Tee returns n sequences that each yield every element of s, which is pulled
+// only once. Elements are buffered until every consumer still attached has seen
+// them, so memory grows with the distance between the fastest and slowest
+// consumer. Each returned sequence can be iterated once; a consumer that stops
+// or is exhausted detaches, and a consumer that is never iterated holds the
+// buffer and the source open. The returned sequences may be consumed from
+// different goroutines.
+func Tee[T any](s iter.Seq[T], n int) []iter.Seq[T] {
+	if n < 0 {
+		panic("adapters: Tee n must not be negative")
+	}
+	t := &tee[T]{
+		src:      s,
+		pos:      make([]int, n),
+		detached: make([]bool, n),
+		attached: n,
+	}
+	seqs := make([]iter.Seq[T], n)
+	for i := range seqs {
+		seqs[i] = func(yield func(T) bool) {
+			defer t.detach(i)
+			for {
+				v, ok := t.get(i)
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+	return seqs
+}
+
+type tee[T any] struct {
+	mu        sync.Mutex
+	src       iter.Seq[T]
+	next      func() (T, bool)
+	stop      func()
+	exhausted bool
+	buf       []T
+	base      int
+	pos       []int
+	detached  []bool
+	attached  int
+}
+
+func (t *tee[T]) get(i int) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var zero T
+	if t.detached[i] {
+		return zero, false
+	}
+	if off := t.pos[i] - t.base; off < len(t.buf) {
+		v := t.buf[off]
+		t.pos[i]++
+		t.trim()
+		return v, true
+	}
+	if t.exhausted {
+		return zero, false
+	}
+	if t.next == nil {
+		t.next, t.stop = iter.Pull(t.src)
+	}
+	v, ok := t.next()
+	if !ok {
+		t.exhausted = true
+		t.stop()
+		return zero, false
+	}
+	t.buf = append(t.buf, v)
+	t.pos[i]++
+	t.trim()
+	return v, true
+}
+
+func (t *tee[T]) detach(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.detached[i] {
+		return
+	}
+	t.detached[i] = true
+	t.attached--
+	if t.attached == 0 && t.stop != nil {
+		t.stop()
+	}
+	t.trim()
+}
+
+func (t *tee[T]) trim() {
+	low := t.base + len(t.buf)
+	for i, p := range t.pos {
+		if !t.detached[i] && p < low {
+			low = p
+		}
+	}
+	drop := low - t.base
+	clear(t.buf[:drop])
+	t.buf = t.buf[drop:]
+	t.base = low
+}
+
+func Tap[T any](s iter.Seq[T], fn func(T)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			fn(v)
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func Tap2[K, V any](s iter.Seq2[K, V], fn func(K, V)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range s {
+			fn(k, v)
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func Pairwise[T any](s iter.Seq[T]) iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		next, stop := iter.Pull(s)
+		defer stop()
+
+		prev, ok := next()
+		if !ok {
+			return
+		}
+		for {
+			v, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(prev, v) {
+				return
+			}
+			prev = v
+		}
+	}
+}
+
+// StepBy yields the elements at indices 0, step, 2*step, ... It panics if
+// step <= 0.
+func StepBy[T any](s iter.Seq[T], step int) iter.Seq[T] {
+	if step <= 0 {
+		panic("adapters: StepBy step must be positive")
+	}
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range s {
+			if i%step == 0 {
+				if !yield(v) {
+					return
+				}
+			}
+			i++
+		}
+	}
+}
+
+func Intersperse[T any](s iter.Seq[T], sep T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		first := true
+		for v := range s {
+			if !first && !yield(sep) {
+				return
+			}
+			first = false
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Product2 yields every (x, y) pair with x from a and y from b, in that nesting
+// order. b is buffered in full while pairing it with the first element of a and
+// replayed for the rest.
+func Product2[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		var buf []B
+		buffered := false
+		for x := range a {
+			if !buffered {
+				for y := range b {
+					buf = append(buf, y)
+					if !yield(x, y) {
+						return
+					}
+				}
+				if len(buf) == 0 {
+					return
+				}
+				buffered = true
+				continue
+			}
+			for _, y := range buf {
+				if !yield(x, y) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func Swap[K, V any](s iter.Seq2[K, V]) iter.Seq2[V, K] {
+	return func(yield func(V, K) bool) {
+		for k, v := range s {
+			if !yield(v, k) {
+				return
+			}
+		}
+	}
+}
+
+func MapKeys[K1, V, K2 any](s iter.Seq2[K1, V], fn func(K1) K2) iter.Seq2[K2, V] {
+	return func(yield func(K2, V) bool) {
+		for k, v := range s {
+			if !yield(fn(k), v) {
+				return
+			}
+		}
+	}
+}
+
+func MapValues[K, V1, V2 any](s iter.Seq2[K, V1], fn func(V1) V2) iter.Seq2[K, V2] {
+	return func(yield func(K, V2) bool) {
+		for k, v := range s {
+			if !yield(k, fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+func FilterKeys[K, V any](s iter.Seq2[K, V], pred func(K) bool) iter.Seq2[K, V] {
+	return Filter2(s, func(k K, _ V) bool { return pred(k) })
+}
+
+func FilterValues[K, V any](s iter.Seq2[K, V], pred func(V) bool) iter.Seq2[K, V] {
+	return Filter2(s, func(_ K, v V) bool { return pred(v) })
+}
+
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+func Entries[K, V any](s iter.Seq2[K, V]) iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		for k, v := range s {
+			if !yield(Pair[K, V]{Key: k, Value: v}) {
+				return
+			}
+		}
+	}
+}
+
+func FromEntries[K, V any](s iter.Seq[Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for p := range s {
+			if !yield(p.Key, p.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ConcatSeq flattens a sequence of sequences, starting each inner sequence
+// only once the outer one reaches it. It is equivalent to FlattenSeq.
+func ConcatSeq[T any](s iter.Seq[iter.Seq[T]]) iter.Seq[T] {
+	return FlattenSeq(s)
+}
+
+// BatchTimed groups elements into batches of up to maxSize, emitting a batch
+// early once maxWait has passed since its first element arrived. Any partial
+// batch is emitted when s is exhausted. s is iterated on its own goroutine,
+// which exits once the consumer stops and s yields its next element or
+// returns. BatchTimed panics if maxSize <= 0.
+func BatchTimed[T any](s iter.Seq[T], maxSize int, maxWait time.Duration) iter.Seq[[]T] {
+	if maxSize <= 0 {
+		panic("adapters: BatchTimed maxSize must be positive")
+	}
+	return func(yield func([]T) bool) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := ToChannel(ctx, s, 0)
+
+		timer := time.NewTimer(maxWait)
+		timer.Stop()
+		defer timer.Stop()
+		var timeout <-chan time.Time
+
+		batch := make([]T, 0, min(maxSize, maxPrealloc))
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					if len(batch) > 0 {
+						yield(batch)
+					}
+					return
+				}
+				if len(batch) == 0 {
+					timer.Reset(maxWait)
+					timeout = timer.C
+				}
+				batch = append(batch, v)
+				if len(batch) < maxSize {
+					continue
+				}
+			case <-timeout:
+			}
+			timer.Stop()
+			timeout = nil
+			if !yield(batch) {
+				return
+			}
+			batch = make([]T, 0, min(maxSize, maxPrealloc))
+		}
+	}
+}
+
+// DistinctWindow suppresses a value if it is among the last windowSize
+// elements yielded. Values that have fallen out of the window can be yielded
+// again. Memory is bounded by windowSize. DistinctWindow panics if
+// windowSize <= 0.
+func DistinctWindow[T comparable](s iter.Seq[T], windowSize int) iter.Seq[T] {
+	if windowSize <= 0 {
+		panic("adapters: DistinctWindow windowSize must be positive")
+	}
+	return func(yield func(T) bool) {
+		ring := make([]T, 0, min(windowSize, maxPrealloc))
+		counts := make(map[T]int)
+		head := 0
+		for v := range s {
+			if counts[v] > 0 {
+				continue
+			}
+			if len(ring) < windowSize {
+				ring = append(ring, v)
+			} else {
+				old := ring[head]
+				if counts[old]--; counts[old] == 0 {
+					delete(counts, old)
+				}
+				ring[head] = v
+				head = (head + 1) % windowSize
+			}
+			counts[v]++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func Frequencies[T comparable](s iter.Seq[T]) map[T]int {
+	return FrequenciesBy(s, func(v T) T { return v })
+}
+
+func FrequenciesBy[T any, K comparable](s iter.Seq[T], key func(T) K) map[K]int {
+	result := make(map[K]int)
+	for v := range s {
+		result[key(v)]++
+	}
+	return result
+}
+
+// Average returns the arithmetic mean of s. The bool is false if s is empty.
+func Average[T Numeric](s iter.Seq[T]) (float64, bool) {
+	sum, count := 0.0, 0
+	for v := range s {
+		sum += float64(v)
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// MovingAverage yields the mean of the last window elements each time a new
+// element arrives, starting once window elements have been seen. It keeps a
+// running sum instead of re-summing each window. MovingAverage panics if
+// window <= 0.
+func MovingAverage[T Numeric](s iter.Seq[T], window int) iter.Seq[float64] {
+	if window <= 0 {
+		panic("adapters: MovingAverage window must be positive")
+	}
+	return func(yield func(float64) bool) {
+		ring := make([]float64, 0, min(window, maxPrealloc))
+		sum := 0.0
+		count := 0
+		for v := range s {
+			f := float64(v)
+			if len(ring) < window {
+				ring = append(ring, f)
+				sum += f
+			} else {
+				i := count % window
+				sum += f - ring[i]
+				ring[i] = f
+			}
+			count++
+			if count < window {
+				continue
+			}
+			if !yield(sum / float64(window)) {
+				return
+			}
+		}
+	}
+}
+
+// MapChunks splits s into chunks of chunkSize, applies fn to the chunks across
+// workers goroutines as MapParallel does, and yields the flattened results in
+// input order.
+func MapChunks[T, R any](s iter.Seq[T], chunkSize, workers int, fn func([]T) []R) iter.Seq[R] {
+	return FlattenSlices(MapParallel(Chunk(s, chunkSize), workers, fn))
+}
+
+// MapErr is like FilterMap but yields the error from transform alongside each
+// result instead of dropping the element.
+func MapErr[T, R any](s iter.Seq[T], transform func(T) (R, error)) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		for v := range s {
+			if !yield(transform(v)) {
+				return
+			}
+		}
+	}
+}
+
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// MapResult applies fn to the value of each successful Result. Failed Results
+// are passed through without calling fn.
+func MapResult[T, R any](s iter.Seq[Result[T]], fn func(T) (R, error)) iter.Seq[Result[R]] {
+	return func(yield func(Result[R]) bool) {
+		for r := range s {
+			var out Result[R]
+			if r.Err != nil {
+				out.Err = r.Err
+			} else {
+				out.Value, out.Err = fn(r.Value)
+			}
+			if !yield(out) {
+				return
+			}
+		}
+	}
+}
+
+// CollectResults drains s into a slice. At the first failed Result it stops and
+// returns the values collected so far along with that error.
+func CollectResults[T any](s iter.Seq[Result[T]]) ([]T, error) {
+	var result []T
+	for r := range s {
+		if r.Err != nil {
+			return result, r.Err
+		}
+		result = append(result, r.Value)
+	}
+	return result, nil
+}
+
+// CollectCtx drains s into a slice, checking ctx before each element. If ctx is
+// done, it stops and returns the elements collected so far with ctx.Err().
+func CollectCtx[T any](ctx context.Context, s iter.Seq[T]) ([]T, error) {
+	var result []T
+	for v := range s {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// Collect2Ctx is the CollectMap counterpart of CollectCtx. Later values
+// overwrite earlier ones for the same key.
+func Collect2Ctx[K comparable, V any](ctx context.Context, s iter.Seq2[K, V]) (map[K]V, error) {
+	result := make(map[K]V)
+	for k, v := range s {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// TakeLast yields the final n elements of s, buffering up to n elements while s
+// is drained.
+func TakeLast[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		ring := make([]T, 0, min(n, maxPrealloc))
+		head := 0
+		for v := range s {
+			if len(ring) < n {
+				ring = append(ring, v)
+				continue
+			}
+			ring[head] = v
+			head = (head + 1) % n
+		}
+		for i := range len(ring) {
+			if !yield(ring[(head+i)%len(ring)]) {
+				return
+			}
+		}
+	}
+}
+
+// SkipLast yields all but the final n elements of s, holding back up to n
+// elements at a time.
+func SkipLast[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			for v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+			return
+		}
+		ring := make([]T, 0, min(n, maxPrealloc))
+		head := 0
+		for v := range s {
+			if len(ring) < n {
+				ring = append(ring, v)
+				continue
+			}
+			out := ring[head]
+			ring[head] = v
+			head = (head + 1) % n
+			if !yield(out) {
+				return
+			}
+		}
+	}
+}
+
+func Equal[T comparable](s1, s2 iter.Seq[T]) bool {
+	return EqualFunc(s1, s2, func(a, b T) bool { return a == b })
+}
+
+func EqualFunc[T, U any](s1 iter.Seq[T], s2 iter.Seq[U], eq func(T, U) bool) bool {
+	next1, stop1 := iter.Pull(s1)
+	next2, stop2 := iter.Pull(s2)
+	defer stop1()
+	defer stop2()
+
+	for {
+		v1, ok1 := next1()
+		v2, ok2 := next2()
+		if !ok1 || !ok2 {
+			return ok1 == ok2
+		}
+		if !eq(v1, v2) {
+			return false
+		}
+	}
+}
+
+// Union yields each distinct element of a, then each distinct element of b not
+// already yielded.
+func Union[T comparable](a, b iter.Seq[T]) iter.Seq[T] {
+	return Distinct(Chain(a, b))
+}
+
+// Intersect yields each distinct element of a that also occurs in b, in the
+// order of a. b is buffered into a set before a is read.
+func Intersect[T comparable](a, b iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		set := toSet(b)
+		for v := range Distinct(a) {
+			if _, ok := set[v]; !ok {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Difference yields each distinct element of a that does not occur in b, in
+// the order of a. b is buffered into a set before a is read.
+func Difference[T comparable](a, b iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		set := toSet(b)
+		for v := range Distinct(a) {
+			if _, ok := set[v]; ok {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func toSet[T comparable](s iter.Seq[T]) map[T]struct{} {
+	set := make(map[T]struct{})
+	for v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+func MergeSorted[T cmp.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return MergeSortedFunc(cmp.Compare[T], seqs...)
+}
+
+// MergeSortedFunc lazily merges sequences that are each already sorted by
+// compare. Equal elements are yielded in the order their sequences were given.
+func MergeSortedFunc[T any](compare func(a, b T) int, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := &mergeHeap[T]{compare: compare}
+		for i, s := range seqs {
+			next, stop := iter.Pull(s)
+			defer stop()
+			if v, ok := next(); ok {
+				h.items = append(h.items, mergeHead[T]{v: v, idx: i, next: next})
+			}
+		}
+		heap.Init(h)
+
+		for h.Len() > 0 {
+			top := &h.items[0]
+			if !yield(top.v) {
+				return
+			}
+			if v, ok := top.next(); ok {
+				top.v = v
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+	}
+}
+
+type mergeHead[T any] struct {
+	v    T
+	idx  int
+	next func() (T, bool)
+}
+
+type mergeHeap[T any] struct {
+	items   []mergeHead[T]
+	compare func(a, b T) int
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if c := h.compare(h.items[i].v, h.items[j].v); c != 0 {
+		return c < 0
+	}
+	return h.items[i].idx < h.items[j].idx
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeHead[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// Interleave yields one element from each sequence in turn, dropping sequences
+// as they are exhausted, until all of them are.
+func Interleave[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		live := make([]func() (T, bool), 0, len(seqs))
+		for _, s := range seqs {
+			next, stop := iter.Pull(s)
+			defer stop()
+			live = append(live, next)
+		}
+
+		for len(live) > 0 {
+			i := 0
+			for _, next := range live {
+				v, ok := next()
+				if !ok {
+					continue
+				}
+				live[i] = next
+				i++
+				if !yield(v) {
+					return
+				}
+			}
+			live = live[:i]
+		}
+	}
+}
+
+func Compact[T comparable](s iter.Seq[T]) iter.Seq[T] {
+	var zero T
+	return Filter(s, func(v T) bool { return v != zero })
+}
+
+func CompactFunc[T any](s iter.Seq[T], isZero func(T) bool) iter.Seq[T] {
+	return Filter(s, func(v T) bool { return !isZero(v) })
+}
+
+func RunLengthEncode[T comparable](s iter.Seq[T]) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		next, stop := iter.Pull(s)
+		defer stop()
+
+		run, ok := next()
+		for ok {
+			count := 1
+			var v T
+			for {
+				v, ok = next()
+				if !ok || v != run {
+					break
+				}
+				count++
+			}
+			if !yield(run, count) {
+				return
+			}
+			run = v
+		}
+	}
+}
+
+// RunLengthDecode yields each value count times. Pairs with count <= 0 yield
+// nothing.
+func RunLengthDecode[T any](s iter.Seq2[T, int]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, count := range s {
+			for range count {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ChunkBy yields runs of adjacent elements that share the same key, along with
+// that key. Only the current run is buffered, and each run has its own backing
+// array.
+func ChunkBy[T any, K comparable](s iter.Seq[T], key func(T) K) iter.Seq2[K, []T] {
+	return func(yield func(K, []T) bool) {
+		var current K
+		var chunk []T
+		for v := range s {
+			k := key(v)
+			if len(chunk) > 0 && k != current {
+				if !yield(current, chunk) {
+					return
+				}
+				chunk = nil
+			}
+			current = k
+			chunk = append(chunk, v)
+		}
+		if len(chunk) > 0 {
+			yield(current, chunk)
+		}
+	}
+}
+
+// Progress calls report with the running count after every every elements
+// passed through, and once more with the final count if s is exhausted on an
+// uneven count. Progress panics if every <= 0.
+func Progress[T any](s iter.Seq[T], every int, report func(count int)) iter.Seq[T] {
+	if every <= 0 {
+		panic("adapters: Progress every must be positive")
+	}
+	return func(yield func(T) bool) {
+		count := 0
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+			count++
+			if count%every == 0 {
+				report(count)
+			}
+		}
+		if count%every != 0 {
+			report(count)
+		}
+	}
+}
+
+// Memoize returns a sequence that can be iterated any number of times while s
+// is pulled at most once. Elements are cached as they are first pulled, and
+// later iterations replay the cache before pulling s further. The cache grows
+// without bound, and s is held open until some iteration exhausts it. The
+// returned sequence may be iterated from different goroutines.
+func Memoize[T any](s iter.Seq[T]) iter.Seq[T] {
+	m := &memo[T]{src: s}
+	return func(yield func(T) bool) {
+		for i := 0; ; i++ {
+			v, ok := m.get(i)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+type memo[T any] struct {
+	mu        sync.Mutex
+	src       iter.Seq[T]
+	next      func() (T, bool)
+	stop      func()
+	exhausted bool
+	cache     []T
+}
+
+func (m *memo[T]) get(i int) (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i < len(m.cache) {
+		return m.cache[i], true
+	}
+	var zero T
+	if m.exhausted {
+		return zero, false
+	}
+	if m.next == nil {
+		m.next, m.stop = iter.Pull(m.src)
+	}
+	v, ok := m.next()
+	if !ok {
+		m.exhausted = true
+		m.stop()
+		return zero, false
+	}
+	m.cache = append(m.cache, v)
+	return v, true
+}
+
+func JoinString[T any](s iter.Seq[T], sep string, format func(T) string) string {
+	var b strings.Builder
+	first := true
+	for v := range s {
+		if !first {
+			b.WriteString(sep)
+		}
+		first = false
+		b.WriteString(format(v))
+	}
+	return b.String()
+}
+
+func JoinStrings(s iter.Seq[string], sep string) string {
+	return JoinString(s, sep, func(v string) string { return v })
+}
+
+// Index drains s into a map keyed by key. When two elements share a key, the
+// later one wins.
+func Index[T any, K comparable](s iter.Seq[T], key func(T) K) map[K]T {
+	result := make(map[K]T)
+	for v := range s {
+		result[key(v)] = v
+	}
+	return result
+}
+
+// IndexMulti drains s into a map from each key to all elements with that key,
+// in order. It is equivalent to GroupBy.
+func IndexMulti[T any, K comparable](s iter.Seq[T], key func(T) K) map[K][]T {
+	return GroupBy(s, key)
+}
+
+// Slice yields the elements with index in [start, end), returning as soon as
+// index end-1 has been yielded. A negative start is treated as 0, and a
+// negative end means no upper bound.
+func Slice[T any](s iter.Seq[T], start, end int) iter.Seq[T] {
+	start = max(start, 0)
+	return func(yield func(T) bool) {
+		if end >= 0 && end <= start {
+			return
+		}
+		i := 0
+		for v := range s {
+			if i >= start {
+				if !yield(v) {
+					return
+				}
+			}
+			i++
+			if end >= 0 && i >= end {
+				return
+			}
+		}
+	}
+}
+
+// Span reads the longest prefix of s satisfying pred into a slice and returns
+// the remainder, starting at the first failing element, as a sequence that
+// continues the same pull over s. rest can be iterated once, and s is held
+// open until rest has been iterated or stop is called. Callers that may not
+// range over rest must call stop to release s; once stop has been called, rest
+// yields nothing. stop is safe to call more than once, including after rest
+// has been iterated.
+func Span[T any](s iter.Seq[T], pred func(T) bool) (prefix []T, rest iter.Seq[T], stop func()) {
+	next, stopPull := iter.Pull(s)
+	for {
+		v, ok := next()
+		if !ok {
+			stopPull()
+			return prefix, Empty[T](), stopPull
+		}
+		if pred(v) {
+			prefix = append(prefix, v)
+			continue
+		}
+		done := false
+		stop = func() {
+			done = true
+			stopPull()
+		}
+		rest = func(yield func(T) bool) {
+			if done {
+				return
+			}
+			defer stop()
+			if !yield(v) {
+				return
+			}
+			for {
+				v, ok := next()
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+		return prefix, rest, stop
+	}
+}
+
+// Metrics holds the per-element latency observed by Timed. It is not safe to
+// read while the sequence is still being consumed on another goroutine.
+type Metrics struct {
+	Count int
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+}
+
+func (m *Metrics) Avg() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Count)
+}
+
+func (m *Metrics) record(d time.Duration) {
+	if m.Count == 0 || d < m.Min {
+		m.Min = d
+	}
+	if d > m.Max {
+		m.Max = d
+	}
+	m.Count++
+	m.Total += d
+}
+
+// Timed measures how long s takes to produce each element, that is the time
+// from the consumer asking for an element until s yields it. Time spent by the
+// consumer is not counted.
+func Timed[T any](s iter.Seq[T]) (iter.Seq[T], *Metrics) {
+	m := &Metrics{}
+	return func(yield func(T) bool) {
+		start := time.Now()
+		for v := range s {
+			m.record(time.Since(start))
+			if !yield(v) {
+				return
+			}
+			start = time.Now()
+		}
+	}, m
+}
+
+// Retry calls factory up to attempts times until it returns a sequence,
+// sleeping for backoff(n) after the nth failed attempt, and then yields that
+// sequence's elements with a nil error. If every attempt fails, the last error
+// is yielded with a zero value. An iter.Seq has no way to report a failure
+// after it starts, so once a sequence is obtained it is not retried. attempts
+// below 1 are treated as 1, and a nil backoff retries immediately.
+func Retry[T any](factory func() (iter.Seq[T], error), attempts int, backoff func(attempt int) time.Duration) iter.Seq2[T, error] {
+	attempts = max(attempts, 1)
+	return func(yield func(T, error) bool) {
+		var err error
+		for attempt := 1; attempt <= attempts; attempt++ {
+			var s iter.Seq[T]
+			if s, err = factory(); err == nil {
+				for v := range s {
+					if !yield(v, nil) {
+						return
+					}
+				}
+				return
+			}
+			if attempt < attempts && backoff != nil {
+				time.Sleep(backoff(attempt))
+			}
+		}
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// Debounce yields an element only once quiet has passed without a newer one
+// arriving; elements superseded within that period are dropped. The last
+// element is always yielded when s is exhausted. As with BatchTimed, s is
+// iterated on its own goroutine.
+func Debounce[T any](s iter.Seq[T], quiet time.Duration) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := ToChannel(ctx, s, 0)
+
+		timer := time.NewTimer(quiet)
+		timer.Stop()
+		defer timer.Stop()
+		var timeout <-chan time.Time
+
+		var pending T
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					if timeout != nil {
+						yield(pending)
+					}
+					return
+				}
+				pending = v
+				timer.Reset(quiet)
+				timeout = timer.C
+			case <-timeout:
+				timeout = nil
+				if !yield(pending) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RateLimit yields at most one element per interval. The first element is
+// yielded immediately, and each later one waits until interval has passed
+// since the previous element was yielded. RateLimit panics if interval <= 0.
+func RateLimit[T any](s iter.Seq[T], interval time.Duration) iter.Seq[T] {
+	if interval <= 0 {
+		panic("adapters: RateLimit interval must be positive")
+	}
+	return func(yield func(T) bool) {
+		var last time.Time
+		for v := range s {
+			if !last.IsZero() {
+				if wait := interval - time.Since(last); wait > 0 {
+					time.Sleep(wait)
+				}
+			}
+			last = time.Now()
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// WindowReduce folds each sliding window of size elements, as produced by
+// Window, into a single value with reducer starting from initial. The window is
+// kept in one reused buffer instead of allocating a slice per window.
+// WindowReduce panics if size <= 0.
+func WindowReduce[T, R any](s iter.Seq[T], size int, initial R, reducer func(R, T) R) iter.Seq[R] {
+	if size <= 0 {
+		panic("adapters: WindowReduce size must be positive")
+	}
+	return func(yield func(R) bool) {
+		ring := make([]T, 0, min(size, maxPrealloc))
+		count := 0
+		for v := range s {
+			if len(ring) < size {
+				ring = append(ring, v)
+			} else {
+				ring[count%size] = v
+			}
+			count++
+			if count < size {
+				continue
+			}
+			result := initial
+			for i := range size {
+				result = reducer(result, ring[(count+i)%size])
+			}
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// Unfold generates a sequence from seed. Each call to step returns the next
+// element and the next state, or false to end the sequence.
+func Unfold[S, T any](seed S, step func(S) (T, S, bool)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		state := seed
+		for {
+			v, next, ok := step(state)
+			if !ok || !yield(v) {
+				return
+			}
+			state = next
+		}
+	}
+}
+
+// Iterate yields initial, next(initial), next(next(initial)), ... forever.
+func Iterate[T any](initial T, next func(T) T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := initial; yield(v); v = next(v) {
+		}
+	}
+}
+
+// TopN yields the n most frequent values of s with their counts, most frequent
+// first. Ties are broken by first occurrence. s is counted in full on the first
+// step of iteration.
+func TopN[T comparable](s iter.Seq[T], n int) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		if n <= 0 {
+			return
+		}
+		index := make(map[T]int)
+		var tally []Pair[T, int]
+		for v := range s {
+			i, ok := index[v]
+			if !ok {
+				i = len(tally)
+				index[v] = i
+				tally = append(tally, Pair[T, int]{Key: v})
+			}
+			tally[i].Value++
+		}
+		slices.SortStableFunc(tally, func(a, b Pair[T, int]) int {
+			return cmp.Compare(b.Value, a.Value)
+		})
+		for _, p := range tally[:min(n, len(tally))] {
+			if !yield(p.Key, p.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachParallel applies fn to every element of s across workers goroutines
+// and returns once all calls have finished. Calls are unordered. workers <= 0
+// means runtime.NumCPU().
+func ForEachParallel[T any](s iter.Seq[T], workers int, fn func(T)) {
+	ForEachParallelErr(s, workers, func(v T) error {
+		fn(v)
+		return nil
+	})
+}
+
+// ForEachParallelErr is like ForEachParallel but stops handing out elements
+// once fn returns an error, and returns the first such error after in-flight
+// calls have finished.
+func ForEachParallelErr[T any](s iter.Seq[T], workers int, fn func(T) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var once sync.Once
+	var firstErr error
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := fn(v); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for v := range s {
+		select {
+		case jobs <- v:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// Transpose buffers every row of s and yields its columns. With pad, ragged
+// rows are padded with zero values to the length of the longest row; without
+// it, columns stop at the length of the shortest row.
+func Transpose[T any](s iter.Seq[[]T], pad bool) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		rows := Collect(s)
+		if len(rows) == 0 {
+			return
+		}
+		width := len(rows[0])
+		for _, row := range rows[1:] {
+			if pad {
+				width = max(width, len(row))
+			} else {
+				width = min(width, len(row))
+			}
+		}
+		for j := range width {
+			col := make([]T, len(rows))
+			for i, row := range rows {
+				if j < len(row) {
+					col[i] = row[j]
+				}
+			}
+			if !yield(col) {
+				return
+			}
+		}
+	}
+}
+
+// SampleN returns up to n elements chosen uniformly at random from a single
+// pass over s, using reservoir sampling driven by rng. If s has fewer than n
+// elements, all of them are returned.
+func SampleN[T any](s iter.Seq[T], n int, rng *rand.Rand) []T {
+	if n <= 0 {
+		return nil
+	}
+	var reservoir []T
+	seen := 0
+	for v := range s {
+		seen++
+		if len(reservoir) < n {
+			reservoir = append(reservoir, v)
+			continue
+		}
+		if j := rng.IntN(seen); j < n {
+			reservoir[j] = v
+		}
+	}
+	return reservoir
+}
+
+// Shuffle buffers the whole of s on the first step of iteration and yields it
+// in a random order driven by rng.
+func Shuffle[T any](s iter.Seq[T], rng *rand.Rand) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := Collect(s)
+		rng.Shuffle(len(buf), func(i, j int) { buf[i], buf[j] = buf[j], buf[i] })
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Diff yields the difference between each element and the one before it. It
+// is the inverse of a running Sum built with Scan, minus the first element.
+func Diff[T Numeric](s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for prev, v := range Pairwise(s) {
+			if !yield(v - prev) {
+				return
+			}
+		}
+	}
+}
+
+// DrainN consumes and discards up to n elements of s, returning how many were
+// consumed.
+func DrainN[T any](s iter.Seq[T], n int) int {
+	if n <= 0 {
+		return 0
+	}
+	count := 0
+	for range s {
+		count++
+		if count == n {
+			break
+		}
+	}
+	return count
+}
+
+// GroupByReduce folds the elements of each group into an accumulator starting
+// from initial, keeping only one accumulator per key rather than the members.
+func GroupByReduce[T, R any, K comparable](s iter.Seq[T], key func(T) K, initial R, reducer func(R, T) R) map[K]R {
+	result := make(map[K]R)
+	for v := range s {
+		k := key(v)
+		acc, ok := result[k]
+		if !ok {
+			acc = initial
+		}
+		result[k] = reducer(acc, v)
+	}
+	return result
+}
+
+// Recover ends the sequence gracefully if s panics, passing the recovered value
+// to onPanic. Iteration cannot resume past the panicking element, since s has
+// already unwound. Panics raised by the consumer are not recovered.
+func Recover[T any](s iter.Seq[T], onPanic func(r any)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		inYield := false
+		defer func() {
+			if inYield {
+				return
+			}
+			if r := recover(); r != nil {
+				onPanic(r)
+			}
+		}()
+		for v := range s {
+			inYield = true
+			ok := yield(v)
+			inYield = false
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// LimitTime stops the sequence once budget has elapsed since iteration began.
+// The deadline is checked before each element is yielded, so a source that
+// blocks is not interrupted and can overrun the budget by however long it
+// blocks.
+func LimitTime[T any](s iter.Seq[T], budget time.Duration) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		deadline := time.Now().Add(budget)
+		for v := range s {
+			if !time.Now().Before(deadline) {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// DedupCount behaves like Dedup and also counts the duplicates it drops. The
+// count accumulates across iterations and should be read once consumption is
+// done.
+func DedupCount[T comparable](s iter.Seq[T]) (iter.Seq[T], *int) {
+	removed := new(int)
+	return DedupBy(s, func(a, b T) bool {
+		if a != b {
+			return false
+		}
+		*removed++
+		return true
+	}), removed
+}
+
+// WindowStep yields windows of size consecutive elements, starting a new window
+// every step elements. With step == size it behaves like Chunk, and with
+// step == 1 like Window; a step larger than size skips the elements in between.
+// With keepPartial, windows that start before the end of s but run past it are
+// yielded short; otherwise they are dropped. Each window is a fresh copy.
+// WindowStep panics if size <= 0 or step <= 0.
+func WindowStep[T any](s iter.Seq[T], size, step int, keepPartial bool) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("adapters: WindowStep size must be positive")
+	}
+	if step <= 0 {
+		panic("adapters: WindowStep step must be positive")
+	}
+	return func(yield func([]T) bool) {
+		buf := make([]T, 0, min(size, maxPrealloc))
+		skip := 0
+		for v := range s {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			buf = append(buf, v)
+			if len(buf) < size {
+				continue
+			}
+			if !yield(slices.Clone(buf)) {
+				return
+			}
+			if step >= size {
+				buf = buf[:0]
+				skip = step - size
+			} else {
+				buf = buf[:copy(buf, buf[step:])]
+			}
+		}
+		if !keepPartial {
+			return
+		}
+		for len(buf) > 0 {
+			if !yield(slices.Clone(buf)) {
+				return
+			}
+			if step >= len(buf) {
+				return
+			}
+			buf = buf[step:]
+		}
+	}
+}
+
+// CollectBatchesToChannel sends s in batches of batchSize, the last possibly
+// shorter, on the returned channel as ToChannel does.
+func CollectBatchesToChannel[T any](ctx context.Context, s iter.Seq[T], batchSize, chanBuffer int) <-chan []T {
+	return ToChannel(ctx, Chunk(s, batchSize), chanBuffer)
+}
+
+func Prepend[T any](s iter.Seq[T], vs ...T) iter.Seq[T] {
+	return Chain(FromSlice(vs), s)
+}
+
+func Append[T any](s iter.Seq[T], vs ...T) iter.Seq[T] {
+	return Chain(s, FromSlice(vs))
+}
+
+// Coalesce layers override on top of base. It yields every key of base in the
+// order it first appears there, with the value from override when override has
+// that key, and then the keys only override has, in the order they first
+// appear in override. When an input repeats a key, its last value wins. Both
+// inputs are buffered on the first step of iteration.
+func Coalesce[K comparable, V any](base, override iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		baseValues, baseKeys := collectOrdered(base)
+		overrideValues, overrideKeys := collectOrdered(override)
+		for _, k := range baseKeys {
+			v, ok := overrideValues[k]
+			if !ok {
+				v = baseValues[k]
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+		for _, k := range overrideKeys {
+			if _, ok := baseValues[k]; ok {
+				continue
+			}
+			if !yield(k, overrideValues[k]) {
+				return
+			}
+		}
+	}
+}
+
+func collectOrdered[K comparable, V any](s iter.Seq2[K, V]) (map[K]V, []K) {
+	values := make(map[K]V)
+	var keys []K
+	for k, v := range s {
+		if _, ok := values[k]; !ok {
+			keys = append(keys, k)
+		}
+		values[k] = v
+	}
+	return values, keys
+}
+
+var ErrTimeout = errors.New("adapters: timed out waiting for element")
+
+// TimeoutEach yields each element of s with a nil error. Whenever s takes
+// longer than d to produce the next element, a zero value with ErrTimeout is
+// yielded and waiting continues, so the consumer decides whether to stop. s is
+// iterated on its own goroutine; once the consumer stops, that goroutine exits
+// at the next element s yields, but it stays blocked for as long as s hangs.
+func TimeoutEach[T any](s iter.Seq[T], d time.Duration) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := ToChannel(ctx, s, 0)
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		var zero T
+		for {
+			timer.Reset(d)
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !yield(v, nil) {
+					return
+				}
+			case <-timer.C:
+				if !yield(zero, ErrTimeout) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SortedDistinct yields the distinct elements of s in ascending order. The
+// whole of s is buffered and sorted on the first step of iteration.
+func SortedDistinct[T cmp.Ordered](s iter.Seq[T]) iter.Seq[T] {
+	return SortedDistinctFunc(s, cmp.Compare[T])
+}
+
+// SortedDistinctFunc yields the elements of s sorted by compare, keeping only
+// the first of each run that compare considers equal. The whole of s is
+// buffered and sorted on the first step of iteration.
+func SortedDistinctFunc[T any](s iter.Seq[T], compare func(a, b T) int) iter.Seq[T] {
+	return DedupBy(SortBy(s, compare), func(a, b T) bool { return compare(a, b) == 0 })
+}
+
+func Replace[T comparable](s iter.Seq[T], old, new T) iter.Seq[T] {
+	return ReplaceFunc(s, func(v T) bool { return v == old }, func(T) T { return new })
+}
+
+func ReplaceFunc[T any](s iter.Seq[T], shouldReplace func(T) bool, replacement func(T) T) iter.Seq[T] {
+	return Map(s, func(v T) T {
+		if shouldReplace(v) {
+			return replacement(v)
+		}
+		return v
+	})
+}
+
+// Rotate yields s starting from index n modulo its length, wrapping around to
+// the elements before it. A negative n counts from the end. The whole of s is
+// buffered on the first step of iteration.
+func Rotate[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := Collect(s)
+		if len(buf) == 0 {
+			return
+		}
+		start := ((n % len(buf)) + len(buf)) % len(buf)
+		for i := range len(buf) {
+			if !yield(buf[(start+i)%len(buf)]) {
+				return
+			}
+		}
+	}
+}
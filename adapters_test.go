@@ -0,0 +1,699 @@
+package adapters
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitGoroutines fails t unless the goroutine count drops back to want within a
+// second, giving abandoned pump goroutines time to exit.
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, want %d", runtime.NumGoroutine(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestChunkHugeSize(t *testing.T) {
+	got := Collect(Chunk(Of(1, 2, 3), math.MaxInt))
+	if len(got) != 1 || !slices.Equal(got[0], []int{1, 2, 3}) {
+		t.Fatalf("got %v, want [[1 2 3]]", got)
+	}
+}
+
+func TestWindowHugeSize(t *testing.T) {
+	if got := Collect(Window(Of(1, 2, 3), math.MaxInt)); len(got) != 0 {
+		t.Fatalf("got %v, want no windows", got)
+	}
+}
+
+func TestDistinctWindowHugeSize(t *testing.T) {
+	got := Collect(DistinctWindow(Of(1, 2, 1, 3), math.MaxInt))
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("got %v, want [1 2 3]", got)
+	}
+}
+
+func TestRangeStep(t *testing.T) {
+	tests := []struct {
+		start, end, step int
+		want             []int
+	}{
+		{0, 5, 2, []int{0, 2, 4}},
+		{0, 6, 2, []int{0, 2, 4}},
+		{5, 0, -2, []int{5, 3, 1}},
+		{0, 0, 1, nil},
+		{math.MaxInt - 3, math.MaxInt, 2, []int{math.MaxInt - 3, math.MaxInt - 1}},
+		{math.MinInt + 3, math.MinInt, -2, []int{math.MinInt + 3, math.MinInt + 1}},
+		{math.MinInt, math.MaxInt, math.MaxInt, []int{math.MinInt, -1, math.MaxInt - 1}},
+		{0, 1, math.MaxInt, []int{0}},
+		{0, -1, math.MinInt, []int{0}},
+	}
+	for _, tt := range tests {
+		got := Collect(Take(RangeStep(tt.start, tt.end, tt.step), 10))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("RangeStep(%d, %d, %d) = %v, want %v", tt.start, tt.end, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestMapParallelOrder(t *testing.T) {
+	base := runtime.NumGoroutine()
+	got := Collect(MapParallel(Range(0, 500), 8, func(i int) int {
+		if i%7 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return i * 2
+	}))
+	if !slices.Equal(got, Collect(Map(Range(0, 500), func(i int) int { return i * 2 }))) {
+		t.Fatalf("results out of order: %v", got)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestMapParallelBreakStopsSource(t *testing.T) {
+	base := runtime.NumGoroutine()
+	var pulls int
+	var finished bool
+	for v := range MapParallel(countingSource(1000, &pulls, &finished), 4, func(i int) int { return i }) {
+		if v != 0 {
+			t.Errorf("first result = %d, want 0", v)
+		}
+		break
+	}
+	if !finished {
+		t.Error("source still running after MapParallel returned")
+	}
+	if pulls > 5 {
+		t.Errorf("source produced %d elements, want at most workers+1", pulls)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestMapParallelBreakThenMutateSource(t *testing.T) {
+	xs := Collect(Range(0, 100))
+	for range MapParallel(FromSlice(xs), 4, func(i int) int { return i }) {
+		break
+	}
+	// Neither the source nor any transform may still be reading xs here; the
+	// race detector flags this write otherwise.
+	xs[0] = 1
+}
+
+// countingSource yields 0..n-1, counting elements produced in *pulls and
+// setting *finished once the source function returns.
+func countingSource(n int, pulls *int, finished *bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		defer func() { *finished = true }()
+		for i := range n {
+			*pulls++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestTeeConcurrentConsumers(t *testing.T) {
+	var pulls int
+	var finished bool
+	seqs := Tee(countingSource(1000, &pulls, &finished), 3)
+
+	results := make([][]int, len(seqs))
+	var wg sync.WaitGroup
+	for i, s := range seqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = Collect(s)
+		}()
+	}
+	wg.Wait()
+
+	want := Collect(Range(0, 1000))
+	for i, got := range results {
+		if !slices.Equal(got, want) {
+			t.Errorf("consumer %d got %d elements, want %d", i, len(got), len(want))
+		}
+	}
+	if pulls != 1000 {
+		t.Errorf("source produced %d elements, want 1000", pulls)
+	}
+	if !finished {
+		t.Error("source was not finished")
+	}
+}
+
+func TestTeeEarlyDetach(t *testing.T) {
+	var pulls int
+	var finished bool
+	seqs := Tee(countingSource(100, &pulls, &finished), 2)
+
+	var wg sync.WaitGroup
+	var short, full []int
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		short = Collect(Take(seqs[0], 3))
+	}()
+	go func() {
+		defer wg.Done()
+		full = Collect(seqs[1])
+	}()
+	wg.Wait()
+
+	if !slices.Equal(short, []int{0, 1, 2}) {
+		t.Errorf("detached consumer got %v, want [0 1 2]", short)
+	}
+	if len(full) != 100 {
+		t.Errorf("remaining consumer got %d elements, want 100", len(full))
+	}
+	if got := Collect(seqs[0]); len(got) != 0 {
+		t.Errorf("re-iterating a detached consumer yielded %v", got)
+	}
+}
+
+func TestTeeAllDetachStopsSource(t *testing.T) {
+	var pulls int
+	var finished bool
+	seqs := Tee(countingSource(100, &pulls, &finished), 2)
+
+	var wg sync.WaitGroup
+	for _, s := range seqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range s {
+				break
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !finished {
+		t.Error("source still open after every consumer detached")
+	}
+	if pulls != 1 {
+		t.Errorf("source produced %d elements, want 1", pulls)
+	}
+}
+
+func TestSwapEmpty(t *testing.T) {
+	for k, v := range Swap(FromMap(map[string]int{})) {
+		t.Fatalf("unexpected pair (%v, %v)", k, v)
+	}
+}
+
+func TestSwapEarlyTermination(t *testing.T) {
+	var got []Pair[int, string]
+	for v, k := range Swap(FromEntries(Of(Pair[string, int]{"a", 1}, Pair[string, int]{"b", 2}))) {
+		got = append(got, Pair[int, string]{v, k})
+		break
+	}
+	if !slices.Equal(got, []Pair[int, string]{{1, "a"}}) {
+		t.Fatalf("got %v, want [{1 a}]", got)
+	}
+}
+
+func TestMapKeysCapturedState(t *testing.T) {
+	calls := 0
+	s := MapKeys(Enumerate(Of("a", "b", "c", "d")), func(k int) int {
+		calls++
+		return k * 10
+	})
+
+	var keys []int
+	for k := range s {
+		keys = append(keys, k)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(keys, []int{0, 10}) {
+		t.Errorf("keys = %v, want [0 10]", keys)
+	}
+	if calls != 2 {
+		t.Errorf("transform called %d times, want 2", calls)
+	}
+
+	if got := Collect(Keys(s)); !slices.Equal(got, []int{0, 10, 20, 30}) {
+		t.Errorf("keys = %v, want [0 10 20 30]", got)
+	}
+	if calls != 6 {
+		t.Errorf("transform called %d times, want 6", calls)
+	}
+}
+
+func TestMapValuesCapturedState(t *testing.T) {
+	calls := 0
+	s := MapValues(Enumerate(Of("a", "b", "c", "d")), func(v string) string {
+		calls++
+		return v + v
+	})
+
+	var values []string
+	for _, v := range s {
+		values = append(values, v)
+		if len(values) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(values, []string{"aa", "bb", "cc"}) {
+		t.Errorf("values = %v, want [aa bb cc]", values)
+	}
+	if calls != 3 {
+		t.Errorf("transform called %d times, want 3", calls)
+	}
+
+	if got := Collect(Values(s)); !slices.Equal(got, []string{"aa", "bb", "cc", "dd"}) {
+		t.Errorf("values = %v, want [aa bb cc dd]", got)
+	}
+	if calls != 7 {
+		t.Errorf("transform called %d times, want 7", calls)
+	}
+}
+
+// burstySource yields 0..n-1, pausing for gap before each index in pauses.
+func burstySource(n int, gap time.Duration, pauses ...int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if slices.Contains(pauses, i) {
+				time.Sleep(gap)
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestBatchTimed(t *testing.T) {
+	base := runtime.NumGoroutine()
+	got := Collect(BatchTimed(burstySource(10, 100*time.Millisecond, 3, 7), 4, 20*time.Millisecond))
+	want := [][]int{{0, 1, 2}, {3, 4, 5, 6}, {7, 8, 9}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestBatchTimedHugeSize(t *testing.T) {
+	got := Collect(BatchTimed(Of(1, 2, 3), math.MaxInt, time.Second))
+	if len(got) != 1 || !slices.Equal(got[0], []int{1, 2, 3}) {
+		t.Fatalf("got %v, want [[1 2 3]]", got)
+	}
+}
+
+func TestBatchTimedBreak(t *testing.T) {
+	base := runtime.NumGoroutine()
+	for b := range BatchTimed(Range(0, 1_000_000), 2, time.Second) {
+		if !slices.Equal(b, []int{0, 1}) {
+			t.Errorf("first batch = %v, want [0 1]", b)
+		}
+		break
+	}
+	waitGoroutines(t, base)
+}
+
+func TestMovingAverage(t *testing.T) {
+	got := Collect(MovingAverage(Of(1, 2, 3, 4, 5), 2))
+	if want := []float64{1.5, 2.5, 3.5, 4.5}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := Collect(MovingAverage(Of(1, 2, 3), math.MaxInt)); len(got) != 0 {
+		t.Errorf("got %v, want nothing for a window longer than the input", got)
+	}
+}
+
+func TestTakeLastSkipLast(t *testing.T) {
+	tests := []struct {
+		n              int
+		last, skipLast []int
+	}{
+		{-1, nil, []int{0, 1, 2, 3, 4}},
+		{0, nil, []int{0, 1, 2, 3, 4}},
+		{2, []int{3, 4}, []int{0, 1, 2}},
+		{5, []int{0, 1, 2, 3, 4}, nil},
+		{math.MaxInt, []int{0, 1, 2, 3, 4}, nil},
+	}
+	for _, tt := range tests {
+		if got := Collect(TakeLast(Range(0, 5), tt.n)); !slices.Equal(got, tt.last) {
+			t.Errorf("TakeLast(%d) = %v, want %v", tt.n, got, tt.last)
+		}
+		if got := Collect(SkipLast(Range(0, 5), tt.n)); !slices.Equal(got, tt.skipLast) {
+			t.Errorf("SkipLast(%d) = %v, want %v", tt.n, got, tt.skipLast)
+		}
+	}
+}
+
+func TestMemoizeResumesPartialPass(t *testing.T) {
+	var pulls int
+	var finished bool
+	m := Memoize(countingSource(5, &pulls, &finished))
+
+	for range m {
+		break
+	}
+	if pulls != 1 {
+		t.Errorf("after first break source produced %d elements, want 1", pulls)
+	}
+	if got := Collect(m); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("second pass = %v, want [0 1 2 3 4]", got)
+	}
+	if got := Collect(m); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("third pass = %v, want [0 1 2 3 4]", got)
+	}
+	if pulls != 5 || !finished {
+		t.Errorf("source produced %d elements (finished %v), want 5 and finished", pulls, finished)
+	}
+}
+
+func TestMemoizeConcurrentConsumers(t *testing.T) {
+	var pulls int
+	var finished bool
+	m := Memoize(countingSource(1000, &pulls, &finished))
+
+	results := make([][]int, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = Collect(m)
+		}()
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if len(got) != 1000 || !slices.IsSorted(got) {
+			t.Errorf("consumer %d got %d elements", i, len(got))
+		}
+	}
+	if pulls != 1000 {
+		t.Errorf("source produced %d elements, want 1000", pulls)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	var pulls int
+	var finished bool
+	prefix, rest, stop := Span(countingSource(6, &pulls, &finished), func(i int) bool { return i < 3 })
+	defer stop()
+	if !slices.Equal(prefix, []int{0, 1, 2}) {
+		t.Errorf("prefix = %v, want [0 1 2]", prefix)
+	}
+	if pulls != 4 {
+		t.Errorf("source produced %d elements before rest, want 4", pulls)
+	}
+	if got := Collect(rest); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Errorf("rest = %v, want [3 4 5]", got)
+	}
+	if got := Collect(rest); len(got) != 0 {
+		t.Errorf("second pass over rest = %v, want nothing", got)
+	}
+	if !finished {
+		t.Error("source still open after rest was exhausted")
+	}
+}
+
+func TestSpanWholeSource(t *testing.T) {
+	prefix, rest, stop := Span(Of(1, 2, 3), func(int) bool { return true })
+	defer stop()
+	if !slices.Equal(prefix, []int{1, 2, 3}) {
+		t.Errorf("prefix = %v, want [1 2 3]", prefix)
+	}
+	if got := Collect(rest); len(got) != 0 {
+		t.Errorf("rest = %v, want nothing", got)
+	}
+}
+
+func TestSpanStopWithoutRest(t *testing.T) {
+	var pulls int
+	var finished bool
+	_, rest, stop := Span(countingSource(6, &pulls, &finished), func(i int) bool { return i < 2 })
+	stop()
+	if !finished {
+		t.Error("source still open after stop")
+	}
+	if got := Collect(rest); len(got) != 0 {
+		t.Errorf("rest after stop = %v, want nothing", got)
+	}
+	stop()
+}
+
+func TestDebounce(t *testing.T) {
+	base := runtime.NumGoroutine()
+	got := Collect(Debounce(burstySource(10, 100*time.Millisecond, 3, 7), 20*time.Millisecond))
+	if want := []int{2, 6, 9}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestDebounceBreak(t *testing.T) {
+	base := runtime.NumGoroutine()
+	for v := range Debounce(burstySource(10, 100*time.Millisecond, 3, 7), 20*time.Millisecond) {
+		if v != 2 {
+			t.Errorf("first settled value = %d, want 2", v)
+		}
+		break
+	}
+	waitGoroutines(t, base)
+}
+
+func TestRateLimitSpacing(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	var stamps []time.Time
+	for i := range RateLimit(Range(0, 5), interval) {
+		stamps = append(stamps, time.Now())
+		if i == 1 {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	for i := 1; i < len(stamps); i++ {
+		// Allow for scheduling jitter between recording the emission and
+		// observing it here.
+		if gap := stamps[i].Sub(stamps[i-1]); gap < interval-2*time.Millisecond {
+			t.Errorf("gap before element %d = %v, want at least %v", i, gap, interval)
+		}
+	}
+}
+
+func TestWindowReduce(t *testing.T) {
+	concat := func(acc string, v int) string { return acc + string(rune('a'+v)) }
+	got := Collect(WindowReduce(Range(0, 6), 3, "", concat))
+	if want := []string{"abc", "bcd", "cde", "def"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := Collect(WindowReduce(Range(0, 3), math.MaxInt, "", concat)); len(got) != 0 {
+		t.Errorf("got %v, want nothing for a window longer than the input", got)
+	}
+}
+
+func TestForEachParallel(t *testing.T) {
+	base := runtime.NumGoroutine()
+	var sum atomic.Int64
+	ForEachParallel(Range(0, 1000), 4, func(i int) { sum.Add(int64(i)) })
+	if got := sum.Load(); got != 499500 {
+		t.Errorf("sum = %d, want 499500", got)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestForEachParallelErrStops(t *testing.T) {
+	base := runtime.NumGoroutine()
+	errBoom := errors.New("boom")
+	var calls atomic.Int64
+	err := ForEachParallelErr(Range(0, 1_000_000), 4, func(i int) error {
+		calls.Add(1)
+		if i == 50 {
+			return errBoom
+		}
+		return nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("err = %v, want %v", err, errBoom)
+	}
+	if n := calls.Load(); n >= 1_000_000 {
+		t.Errorf("fn called %d times, want work to stop after the error", n)
+	}
+	waitGoroutines(t, base)
+}
+
+func TestWindowStep(t *testing.T) {
+	tests := []struct {
+		size, step  int
+		keepPartial bool
+		want        [][]int
+	}{
+		{3, 1, false, [][]int{{0, 1, 2}, {1, 2, 3}, {2, 3, 4}}},
+		{3, 1, true, [][]int{{0, 1, 2}, {1, 2, 3}, {2, 3, 4}, {3, 4}, {4}}},
+		{2, 2, true, [][]int{{0, 1}, {2, 3}, {4}}},
+		{2, 3, false, [][]int{{0, 1}, {3, 4}}},
+		{math.MaxInt, 1, false, nil},
+		{math.MaxInt, 1, true, [][]int{{0, 1, 2, 3, 4}, {1, 2, 3, 4}, {2, 3, 4}, {3, 4}, {4}}},
+	}
+	for _, tt := range tests {
+		got := Collect(WindowStep(Range(0, 5), tt.size, tt.step, tt.keepPartial))
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("WindowStep(%d, %d, %v) = %v, want %v", tt.size, tt.step, tt.keepPartial, got, tt.want)
+		}
+	}
+}
+
+func TestTimeoutEach(t *testing.T) {
+	base := runtime.NumGoroutine()
+	var values []int
+	timeouts := 0
+	for v, err := range TimeoutEach(burstySource(4, 100*time.Millisecond, 2), 30*time.Millisecond) {
+		switch {
+		case errors.Is(err, ErrTimeout):
+			timeouts++
+		case err != nil:
+			t.Fatalf("unexpected error %v", err)
+		default:
+			values = append(values, v)
+		}
+	}
+	if !slices.Equal(values, []int{0, 1, 2, 3}) {
+		t.Errorf("values = %v, want [0 1 2 3]", values)
+	}
+	if timeouts == 0 {
+		t.Error("no timeout reported for the slow element")
+	}
+	waitGoroutines(t, base)
+}
+
+func TestTimeoutEachBreakOnHungSource(t *testing.T) {
+	base := runtime.NumGoroutine()
+	ch := make(chan int)
+	for _, err := range TimeoutEach(FromChannel(ch), 10*time.Millisecond) {
+		if !errors.Is(err, ErrTimeout) {
+			t.Fatalf("err = %v, want ErrTimeout", err)
+		}
+		break
+	}
+	close(ch)
+	waitGoroutines(t, base)
+}
+
+func TestMapChunks(t *testing.T) {
+	double := func(chunk []int) []int {
+		out := make([]int, len(chunk))
+		for i, v := range chunk {
+			out[i] = v * 2
+		}
+		return out
+	}
+	got := Collect(MapChunks(Range(0, 10), 3, 2, double))
+	if want := Collect(Map(Range(0, 10), func(i int) int { return i * 2 })); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	xs := Collect(Range(0, 100))
+	for range MapChunks(FromSlice(xs), 3, 4, double) {
+		break
+	}
+	xs[0] = 1
+}
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		start, end int
+		want       []int
+	}{
+		{1, 3, []int{1, 2}},
+		{0, 5, []int{0, 1, 2, 3, 4}},
+		{2, 30, []int{2, 3, 4}},
+		{-2, 2, []int{0, 1}},
+		{3, 3, nil},
+		{4, 2, nil},
+		{3, -1, []int{3, 4}},
+		{-1, -1, []int{0, 1, 2, 3, 4}},
+		{7, 9, nil},
+	}
+	for _, tt := range tests {
+		if got := Collect(Slice(Range(0, 5), tt.start, tt.end)); !slices.Equal(got, tt.want) {
+			t.Errorf("Slice(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestSliceStopsAtEnd(t *testing.T) {
+	var pulls int
+	var finished bool
+	Collect(Slice(countingSource(100, &pulls, &finished), 1, 3))
+	if pulls != 3 {
+		t.Errorf("source produced %d elements, want 3", pulls)
+	}
+}
+
+func TestRecoverUpstreamPanic(t *testing.T) {
+	src := Map(Range(0, 5), func(i int) int {
+		if i == 3 {
+			panic("bad element")
+		}
+		return i
+	})
+	var recovered []any
+	got := Collect(Recover(src, func(r any) { recovered = append(recovered, r) }))
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("got %v, want [0 1 2]", got)
+	}
+	if len(recovered) != 1 || recovered[0] != "bad element" {
+		t.Errorf("onPanic received %v, want [bad element]", recovered)
+	}
+}
+
+func TestRecoverConsumerPanicPropagates(t *testing.T) {
+	called := false
+	defer func() {
+		if r := recover(); r != "consumer" {
+			t.Errorf("recovered %v, want the consumer's panic", r)
+		}
+		if called {
+			t.Error("onPanic called for a panic raised by the consumer")
+		}
+	}()
+	for v := range Recover(Range(0, 5), func(any) { called = true }) {
+		if v == 2 {
+			panic("consumer")
+		}
+	}
+	t.Fatal("consumer panic was swallowed")
+}
+
+func TestFlattenHandledCases(t *testing.T) {
+	seq := Of[int64](3, 4)
+	slice := []int64{6, 7}
+	var nilSeq *iter.Seq[int64]
+	var nilSlice *[]int64
+	items := Of[any](int64(1), []int64{2}, seq, &seq, int64(5), slice, &slice, nilSeq, nilSlice)
+	got := Collect(Flatten[int64](items))
+	if want := []int64{1, 2, 3, 4, 3, 4, 5, 6, 7, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFlattenPanicsOnUnhandledType(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "[]int") || !strings.Contains(msg, "int64") {
+			t.Errorf("panic message %q does not name both types", msg)
+		}
+	}()
+	Collect(Flatten[int64](Of[any](int64(1), []int{2})))
+	t.Fatal("Flatten did not panic")
+}